@@ -0,0 +1,91 @@
+package commands
+
+import "fmt"
+
+// NoArgs returns an error if any positional arguments were given.
+func NoArgs(cmd *Command, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unknown command %q for %q", args[0], cmd.Name)
+	}
+	return nil
+}
+
+// ArbitraryArgs never returns an error, accepting any positional args.
+func ArbitraryArgs(cmd *Command, args []string) error {
+	return nil
+}
+
+// MinimumNArgs returns a PositionalArgs that errors if fewer than n
+// arguments are given.
+func MinimumNArgs(n int) func(cmd *Command, args []string) error {
+	return func(cmd *Command, args []string) error {
+		if len(args) < n {
+			return fmt.Errorf("%q requires at least %d arg(s), received %d", cmd.Name, n, len(args))
+		}
+		return nil
+	}
+}
+
+// MaximumNArgs returns a PositionalArgs that errors if more than n
+// arguments are given.
+func MaximumNArgs(n int) func(cmd *Command, args []string) error {
+	return func(cmd *Command, args []string) error {
+		if len(args) > n {
+			return fmt.Errorf("%q accepts at most %d arg(s), received %d", cmd.Name, n, len(args))
+		}
+		return nil
+	}
+}
+
+// ExactArgs returns a PositionalArgs that errors if the number of
+// arguments is not exactly n.
+func ExactArgs(n int) func(cmd *Command, args []string) error {
+	return func(cmd *Command, args []string) error {
+		if len(args) != n {
+			return fmt.Errorf("%q requires exactly %d arg(s), received %d", cmd.Name, n, len(args))
+		}
+		return nil
+	}
+}
+
+// RangeArgs returns a PositionalArgs that errors if the number of
+// arguments is not between min and max, inclusive.
+func RangeArgs(min, max int) func(cmd *Command, args []string) error {
+	return func(cmd *Command, args []string) error {
+		if len(args) < min || len(args) > max {
+			return fmt.Errorf("%q requires between %d and %d arg(s), received %d", cmd.Name, min, max, len(args))
+		}
+		return nil
+	}
+}
+
+// OnlyValidArgs returns an error if any positional argument is not
+// present in cmd.ValidArgs.
+func OnlyValidArgs(cmd *Command, args []string) error {
+	for _, arg := range args {
+		valid := false
+		for _, v := range cmd.ValidArgs {
+			if arg == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid argument %q for %q", arg, cmd.Name)
+		}
+	}
+	return nil
+}
+
+// MatchAll returns a PositionalArgs that passes only if every validator
+// in fns passes.
+func MatchAll(fns ...func(cmd *Command, args []string) error) func(cmd *Command, args []string) error {
+	return func(cmd *Command, args []string) error {
+		for _, fn := range fns {
+			if err := fn(cmd, args); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
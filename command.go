@@ -27,6 +27,16 @@ type CommandFunc func(
 	stderr io.Writer,
 ) (int, error)
 
+// Parser is the flag-parsing interface a Command's Flags must satisfy.
+// Both the standard library *flag.FlagSet and *FlagSet (this package's
+// POSIX-style long/short parser) implement it.
+type Parser interface {
+	Parse([]string) error
+	Args() []string
+	VisitAll(func(*flag.Flag))
+	Lookup(string) *flag.Flag
+}
+
 // Command represents a subcommand. Name, Func, and Short are required.
 type Command struct {
 	// The name of the subcommand. Must conform to the format described by
@@ -54,12 +64,72 @@ type Command struct {
 	// whitespace on both ends before being printed.
 	Long string
 
-	// Flags is the flagset for command.
-	Flags *flag.FlagSet
+	// Flags is the flagset for command. Accepts either a stdlib
+	// *flag.FlagSet or a *FlagSet (this package's POSIX-style
+	// long/short parser).
+	Flags Parser
+
+	// PersistentFlags is a flagset that is inherited by this command and
+	// all of its descendants, in addition to their own Flags.
+	PersistentFlags *flag.FlagSet
+
+	// PreRun runs immediately before Func, after PositionalArgs has been
+	// validated.
+	PreRun CommandFunc
+
+	// PersistentPreRun runs before PreRun, and before the PreRun of any
+	// descendant command that doesn't already skip inherited hooks.
+	PersistentPreRun CommandFunc
+
+	// PostRun runs immediately after Func returns.
+	PostRun CommandFunc
+
+	// PersistentPostRun runs after PostRun, and after the PostRun of any
+	// descendant command.
+	PersistentPostRun CommandFunc
+
+	// Aliases lists alternate names that also dispatch to this command.
+	Aliases []string
+
+	// SuggestionsMinimumDistance is the largest Levenshtein distance an
+	// unrecognized subcommand may be from a registered name or alias
+	// before it stops being offered as a "did you mean" suggestion.
+	// Zero uses the default of 2.
+	SuggestionsMinimumDistance int
+
+	// DisableSuggestions turns off "did you mean" suggestions for
+	// unrecognized subcommands.
+	DisableSuggestions bool
+
+	// PreventDoubleClickLaunch, when set on the root command and true,
+	// checks on Windows whether the process was launched by
+	// double-clicking the binary in Explorer rather than from a
+	// console, and if so prints guidance and exits instead of
+	// dispatching. It has no effect on other platforms.
+	PreventDoubleClickLaunch bool
+
+	// PositionalArgs validates the positional arguments remaining after
+	// flag parsing. If nil, no validation is performed. See NoArgs,
+	// ArbitraryArgs, MinimumNArgs, MaximumNArgs, ExactArgs, RangeArgs,
+	// OnlyValidArgs, and MatchAll for prebuilt validators.
+	PositionalArgs func(cmd *Command, args []string) error
+
+	// ValidArgs is the list of positional arguments accepted by the
+	// command, used by the OnlyValidArgs validator.
+	ValidArgs []string
+
+	// ValidArgsFunc returns dynamic completion candidates for the
+	// command's positional arguments. It is consulted by the completion
+	// subsystem once no more subcommands match.
+	ValidArgsFunc func(args []string, toComplete string) []string
+
+	// FlagCompletion supplies dynamic completion candidates for the
+	// named flag's value.
+	FlagCompletion map[string]CompletionFunc
 
 	parent      *Command
 	children    map[string]*Command
-	longestName int
+	longestName int // display width (see displayWidth) of the widest child name
 
 	// stdin defaults to os.stdin unless overridden
 	stdin io.Reader
@@ -121,31 +191,39 @@ func (cmd *Command) Execute(args []string) (int, error) {
 		cmd.stderr = cmd.parent.stderr
 	}
 
+	if cmd.parent == nil && cmd.PreventDoubleClickLaunch && isDoubleClickLaunch() {
+		fmt.Fprint(cmd.stderr, "This is a command line tool.\n\nYou need to open cmd.exe and run it from there.\n")
+		waitForKeypress(cmd.stdin)
+		return ExitCodeSerious, fmt.Errorf("launched by double-click, not from a console")
+	}
+
 	var err error
 
-	fs := cmd.Flags
-	if fs == nil {
-		fs = flag.NewFlagSet(cmd.Name, flag.ExitOnError)
-	}
-	fs.Usage = func() {
-		showHelp(cmd)
-	}
-	if err = fs.Parse(args[1:]); err != nil {
+	parser := cmd.mergedFlagSet()
+	setUsage(parser, cmd)
+	if err = parser.Parse(args[1:]); err != nil {
 		return ExitCodeSerious, err
 	}
+	fs := rawFlagSet(parser)
 
-	if len(args) == 1 {
+	// rest is the positional args left after flag parsing, so a
+	// persistent flag declared on an ancestor (e.g. --verbose) is
+	// accepted in its usual position before the subcommand name, not
+	// just after it.
+	rest := fs.Args()
+
+	if len(rest) == 0 {
 		if cmd.Func != nil {
-			return cmd.Func(context.TODO(), cmd.stdout, fs, os.Getenv, cmd.stdin, cmd.stderr)
+			return cmd.run(fs)
 		}
 		return helpForCommand(cmd, fs)
 	}
 
-	if args[1] == "help" {
+	if rest[0] == "help" {
 		return helpForCommand(cmd, fs)
 	}
 
-	subcommandName := args[1]
+	subcommandName := rest[0]
 	subcommand, ok := cmd.children[subcommandName]
 	if !ok {
 		// if strings.HasPrefix(args[1], "-") {
@@ -154,12 +232,162 @@ func (cmd *Command) Execute(args []string) (int, error) {
 		// }
 		// fmt.Fprintf(os.Stderr, "[ERROR] '%s' is not a recognized subcommand; see 'help'\n", args[1])
 		// os.Exit(ExitCodeSerious)
+		if !cmd.DisableSuggestions {
+			if suggestion, found := cmd.suggest(subcommandName); found {
+				fmt.Fprintf(cmd.stderr, "unknown command %q for %q\n\nDid you mean this?\n\t%s\n", subcommandName, cmd.Name, suggestion)
+				return ExitCodeSerious, fmt.Errorf("unknown command %q for %q", subcommandName, cmd.Name)
+			}
+		}
 		if cmd.Func != nil {
-			return cmd.Func(context.TODO(), cmd.stdout, fs, os.Getenv, cmd.stdin, cmd.stderr)
+			return cmd.run(fs)
 		}
 		return helpForCommand(cmd, fs)
 	}
-	return subcommand.Execute(args[1:])
+	return subcommand.Execute(rest)
+}
+
+// mergedFlagSet builds the effective Parser used to parse cmd's
+// arguments: cmd's own Flags plus cmd's own PersistentFlags plus every
+// ancestor's PersistentFlags, copied into a fresh set so a flag declared
+// anywhere in the tree (e.g. --verbose on root) is accepted at any depth,
+// including by the command that declares it. If cmd.Flags is a
+// *FlagSet, the merged set is also a *FlagSet so POSIX-style parsing and
+// shorthand carry through; otherwise it's a plain *flag.FlagSet.
+func (cmd *Command) mergedFlagSet() Parser {
+	if posix, ok := cmd.Flags.(*FlagSet); ok {
+		merged := NewFlagSet(cmd.Name)
+		merged.NormalizeFunc = posix.NormalizeFunc
+		posix.FlagSet.VisitAll(func(fl *flag.Flag) {
+			merged.FlagSet.Var(fl.Value, fl.Name, fl.Usage)
+		})
+		for long, short := range posix.shorthand {
+			merged.shorthand[long] = short
+		}
+		copyPersistentFlags(merged.FlagSet, cmd)
+		return merged
+	}
+
+	fs := flag.NewFlagSet(cmd.Name, flag.ExitOnError)
+	if cmd.Flags != nil {
+		cmd.Flags.VisitAll(func(fl *flag.Flag) {
+			fs.Var(fl.Value, fl.Name, fl.Usage)
+		})
+	}
+	copyPersistentFlags(fs, cmd)
+	return fs
+}
+
+// copyMissingFlags copies each flag in src into dst, skipping any name
+// dst already has, so a closer declaration in the tree (the command's
+// own, or a nearer ancestor's) wins over one further up. Returns how
+// many flags it added.
+func copyMissingFlags(dst, src *flag.FlagSet) int {
+	n := 0
+	src.VisitAll(func(fl *flag.Flag) {
+		if dst.Lookup(fl.Name) == nil {
+			dst.Var(fl.Value, fl.Name, fl.Usage)
+			n++
+		}
+	})
+	return n
+}
+
+// copyPersistentFlags copies cmd's own PersistentFlags and every
+// ancestor's PersistentFlags into dst, nearest first, so a flag
+// declared anywhere in the tree is accepted at any depth.
+func copyPersistentFlags(dst *flag.FlagSet, cmd *Command) {
+	if cmd.PersistentFlags != nil {
+		copyMissingFlags(dst, cmd.PersistentFlags)
+	}
+	for p := cmd.parent; p != nil; p = p.parent {
+		if p.PersistentFlags != nil {
+			copyMissingFlags(dst, p.PersistentFlags)
+		}
+	}
+}
+
+// setUsage wires cmd's help output into whichever concrete flag set
+// parser is, so flag-parsing errors display contextual help.
+func setUsage(parser Parser, cmd *Command) {
+	switch v := parser.(type) {
+	case *flag.FlagSet:
+		v.Usage = func() { showHelp(cmd) }
+	case *FlagSet:
+		v.FlagSet.Usage = func() { showHelp(cmd) }
+	}
+}
+
+// rawFlagSet extracts the underlying *flag.FlagSet from parser so it can
+// be handed to a CommandFunc, which is defined in terms of the standard
+// library type regardless of which Parser produced it.
+func rawFlagSet(parser Parser) *flag.FlagSet {
+	switch v := parser.(type) {
+	case *flag.FlagSet:
+		return v
+	case *FlagSet:
+		return v.FlagSet
+	default:
+		// mergedFlagSet only ever returns one of the above concrete
+		// types; this is unreachable in practice.
+		return flag.NewFlagSet("", flag.ExitOnError)
+	}
+}
+
+// ancestors returns cmd's parent chain, nearest first, root last.
+func (cmd *Command) ancestors() []*Command {
+	var chain []*Command
+	for p := cmd.parent; p != nil; p = p.parent {
+		chain = append(chain, p)
+	}
+	return chain
+}
+
+// run validates the positional arguments parsed into fs, runs the
+// PreRun/PersistentPreRun hooks, invokes Func, then runs the
+// PostRun/PersistentPostRun hooks.
+func (cmd *Command) run(fs *flag.FlagSet) (int, error) {
+	if cmd.PositionalArgs != nil {
+		if err := cmd.PositionalArgs(cmd, fs.Args()); err != nil {
+			fmt.Fprintln(cmd.stderr, err)
+			showHelp(cmd)
+			return ExitCodeSerious, err
+		}
+	}
+
+	ctx := context.TODO()
+	chain := cmd.ancestors()
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].PersistentPreRun == nil {
+			continue
+		}
+		if code, err := chain[i].PersistentPreRun(ctx, cmd.stdout, fs, os.Getenv, cmd.stdin, cmd.stderr); err != nil {
+			return code, err
+		}
+	}
+	if cmd.PreRun != nil {
+		if code, err := cmd.PreRun(ctx, cmd.stdout, fs, os.Getenv, cmd.stdin, cmd.stderr); err != nil {
+			return code, err
+		}
+	}
+
+	code, err := cmd.Func(ctx, cmd.stdout, fs, os.Getenv, cmd.stdin, cmd.stderr)
+
+	if cmd.PostRun != nil {
+		if _, postErr := cmd.PostRun(ctx, cmd.stdout, fs, os.Getenv, cmd.stdin, cmd.stderr); postErr != nil && err == nil {
+			code, err = ExitCodeSerious, postErr
+		}
+	}
+	for _, ancestor := range chain {
+		if ancestor.PersistentPostRun == nil {
+			continue
+		}
+		if _, postErr := ancestor.PersistentPostRun(ctx, cmd.stdout, fs, os.Getenv, cmd.stdin, cmd.stderr); postErr != nil && err == nil {
+			code, err = ExitCodeSerious, postErr
+		}
+	}
+
+	return code, err
 }
 
 // RegisterChild registers the command cmd. cmd.Name must be unique and
@@ -193,10 +421,18 @@ func (c *Command) RegisterChild(cmd *Command) {
 	if !commandNameRegex.MatchString(cmd.Name) {
 		panic("invalid command name")
 	}
+	for _, alias := range cmd.Aliases {
+		if _, exists := c.children[alias]; exists {
+			panic("command alias already registered: " + alias)
+		}
+	}
 	cmd.parent = c
 	c.children[cmd.Name] = cmd
-	if len(cmd.Name) > c.longestName {
-		c.longestName = len(cmd.Name)
+	for _, alias := range cmd.Aliases {
+		c.children[alias] = cmd
+	}
+	if w := displayWidth(cmd.Name); w > c.longestName {
+		c.longestName = w
 	}
 }
 
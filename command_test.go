@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newTestCommand(name string) *Command {
+	return &Command{
+		Name:  name,
+		Short: name,
+		stdin: strings.NewReader(""),
+	}
+}
+
+func TestMergedFlagSetIncludesOwnPersistentFlags(t *testing.T) {
+	var verbose bool
+	var ran bool
+	root := newTestCommand("root")
+	root.PersistentFlags = flag.NewFlagSet("root", flag.ContinueOnError)
+	root.PersistentFlags.BoolVar(&verbose, "verbose", false, "verbose")
+	root.Func = func(_ context.Context, _ io.Writer, _ *flag.FlagSet, _ func(string) string, _ io.Reader, _ io.Writer) (int, error) {
+		ran = true
+		return ExitCodeSuccess, nil
+	}
+
+	var out bytes.Buffer
+	root.stdout, root.stderr = &out, &out
+
+	code, err := root.Execute([]string{"root", "--verbose"})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if code != ExitCodeSuccess {
+		t.Fatalf("code = %d, want %d", code, ExitCodeSuccess)
+	}
+	if !ran {
+		t.Fatalf("root.Func was not invoked")
+	}
+	if !verbose {
+		t.Fatalf("verbose = false, want true: a command's own PersistentFlags must be usable by itself")
+	}
+}
+
+func TestMergedFlagSetInheritsAncestorPersistentFlags(t *testing.T) {
+	var verbose bool
+	var ran bool
+	root := newTestCommand("root")
+	root.PersistentFlags = flag.NewFlagSet("root", flag.ContinueOnError)
+	root.PersistentFlags.BoolVar(&verbose, "verbose", false, "verbose")
+
+	status := newTestCommand("status")
+	status.Func = func(_ context.Context, _ io.Writer, _ *flag.FlagSet, _ func(string) string, _ io.Reader, _ io.Writer) (int, error) {
+		ran = true
+		return ExitCodeSuccess, nil
+	}
+	root.RegisterChild(status)
+
+	var out bytes.Buffer
+	root.stdout, root.stderr = &out, &out
+
+	// The normal position for a global flag: before the subcommand name.
+	code, err := root.Execute([]string{"root", "--verbose", "status"})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if code != ExitCodeSuccess {
+		t.Fatalf("code = %d, want %d", code, ExitCodeSuccess)
+	}
+	if !ran {
+		t.Fatalf("status.Func was not invoked")
+	}
+	if !verbose {
+		t.Fatalf("verbose = false, want true: inherited persistent flag before the subcommand name should be consumed")
+	}
+}
+
+func TestMergedFlagSetWithPosixFlagsIncludesOwnPersistentFlags(t *testing.T) {
+	var verbose bool
+	var ran bool
+	root := newTestCommand("root")
+	root.Flags = NewFlagSet("root")
+	root.PersistentFlags = flag.NewFlagSet("root", flag.ContinueOnError)
+	root.PersistentFlags.BoolVar(&verbose, "verbose", false, "verbose")
+	root.Func = func(_ context.Context, _ io.Writer, _ *flag.FlagSet, _ func(string) string, _ io.Reader, _ io.Writer) (int, error) {
+		ran = true
+		return ExitCodeSuccess, nil
+	}
+
+	var out bytes.Buffer
+	root.stdout, root.stderr = &out, &out
+
+	if _, err := root.Execute([]string{"root", "--verbose"}); err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if !ran || !verbose {
+		t.Fatalf("ran = %v, verbose = %v, want both true", ran, verbose)
+	}
+}
@@ -0,0 +1,265 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CompletionFunc returns the set of dynamic completion candidates for a
+// flag value given what the user has typed so far.
+type CompletionFunc func(toComplete string) []string
+
+// ShellCompDirective is a hint returned alongside dynamic completion
+// candidates telling the shell script how to treat them.
+type ShellCompDirective int
+
+// ShellCompDirectiveDefault indicates no special handling is needed.
+const ShellCompDirectiveDefault ShellCompDirective = 0
+
+const (
+	// ShellCompDirectiveNoSpace indicates the shell should not add a
+	// trailing space after the completion.
+	ShellCompDirectiveNoSpace ShellCompDirective = 1 << iota
+	// ShellCompDirectiveNoFileComp indicates the shell should not fall
+	// back to file completion when no candidates are returned.
+	ShellCompDirectiveNoFileComp
+)
+
+func init() {
+	RegisterChild(completionCmd)
+
+	// hiddenCompleteCmd bypasses RegisterChild because its "__" prefixed
+	// name doesn't satisfy commandNameRegex; it is never meant to be
+	// typed directly, only invoked by the generated shell scripts.
+	if root.children == nil {
+		root.children = make(map[string]*Command)
+	}
+	hiddenCompleteCmd.parent = root
+	root.children[hiddenCompleteCmd.Name] = hiddenCompleteCmd
+}
+
+var completionCmd = &Command{
+	Name:  "completion",
+	Short: "Generate shell completion scripts",
+	Usage: "<bash|zsh|fish>",
+	Long: `Generate a shell completion script for the requested shell.
+
+To load completions:
+
+  Bash:   source <(name completion bash)
+  Zsh:    name completion zsh > "${fpath[1]}/_name"
+  Fish:   name completion fish | source`,
+	PositionalArgs: ExactArgs(1),
+	Func: func(_ context.Context, stdout io.Writer, fs *flag.FlagSet, _ func(string) string, _ io.Reader, stderr io.Writer) (int, error) {
+		switch shell := fs.Arg(0); shell {
+		case "bash":
+			return ExitCodeSuccess, GenBashCompletion(stdout)
+		case "zsh":
+			return ExitCodeSuccess, GenZshCompletion(stdout)
+		case "fish":
+			return ExitCodeSuccess, GenFishCompletion(stdout)
+		default:
+			fmt.Fprintf(stderr, "unsupported shell %q\n", shell)
+			return ExitCodeSerious, fmt.Errorf("unsupported shell %q", shell)
+		}
+	},
+}
+
+// hiddenCompleteCmd is invoked by the generated shell scripts to obtain
+// dynamic completion candidates. Its name is prefixed with "__" so that
+// showHelp omits it from listings.
+var hiddenCompleteCmd = &Command{
+	Name:  "__complete",
+	Short: "Internal command used by shell completion scripts",
+	Func: func(_ context.Context, stdout io.Writer, fs *flag.FlagSet, _ func(string) string, _ io.Reader, _ io.Writer) (int, error) {
+		args := fs.Args()
+		candidates, directive := completeArgs(root, args)
+		for _, c := range candidates {
+			fmt.Fprintln(stdout, c)
+		}
+		fmt.Fprintf(stdout, ":%d\n", directive)
+		return ExitCodeSuccess, nil
+	},
+}
+
+// completeArgs walks the command tree following args, the same way
+// Command.Execute does, and returns completion candidates for the final
+// (possibly partial) word along with a directive describing them.
+func completeArgs(cmd *Command, args []string) ([]string, ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, ShellCompDirectiveNoFileComp
+	}
+
+	toComplete := args[len(args)-1]
+	rest := args[:len(args)-1]
+
+	// Descend through any already-completed subcommand names.
+	for len(rest) > 0 {
+		child, ok := cmd.children[rest[0]]
+		if !ok {
+			break
+		}
+		cmd = child
+		rest = rest[1:]
+	}
+
+	// A flag is being completed.
+	if strings.HasPrefix(toComplete, "-") {
+		name := strings.TrimLeft(toComplete, "-")
+		if eq := strings.IndexByte(name, '='); eq >= 0 {
+			if fn, ok := cmd.FlagCompletion[name[:eq]]; ok {
+				return fn(name[eq+1:]), ShellCompDirectiveDefault
+			}
+			return nil, ShellCompDirectiveNoFileComp
+		}
+		return flagNameCandidates(cmd, toComplete), ShellCompDirectiveNoSpace
+	}
+
+	// Still have unresolved words but none matched a subcommand: the
+	// previous word may be a flag expecting a value.
+	if len(rest) > 0 {
+		if prev := rest[len(rest)-1]; strings.HasPrefix(prev, "-") {
+			if fn, ok := cmd.FlagCompletion[strings.TrimLeft(prev, "-")]; ok {
+				return fn(toComplete), ShellCompDirectiveDefault
+			}
+		}
+	}
+
+	// Offer subcommand names that are prefixed by what's been typed.
+	if len(cmd.children) > 0 {
+		var names []string
+		for name := range cmd.children {
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+			if strings.HasPrefix(name, toComplete) {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			return names, ShellCompDirectiveNoFileComp
+		}
+	}
+
+	if cmd.ValidArgsFunc != nil {
+		return cmd.ValidArgsFunc(rest, toComplete), ShellCompDirectiveDefault
+	}
+
+	return nil, ShellCompDirectiveDefault
+}
+
+func flagNameCandidates(cmd *Command, toComplete string) []string {
+	prefix := strings.TrimLeft(toComplete, "-")
+	var names []string
+	if cmd.Flags != nil {
+		cmd.Flags.VisitAll(func(fl *flag.Flag) {
+			if strings.HasPrefix(fl.Name, prefix) {
+				names = append(names, "--"+fl.Name)
+			}
+		})
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GenBashCompletion writes a bash completion script for the root command
+// to w.
+func GenBashCompletion(w io.Writer) error {
+	name := root.Name
+	fn := "_" + sanitizeFuncName(name)
+	script := bashCompletionTemplate
+	script = strings.ReplaceAll(script, "{{.Name}}", name)
+	script = strings.ReplaceAll(script, "{{.FuncName}}", fn)
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// GenZshCompletion writes a zsh completion script for the root command
+// to w.
+func GenZshCompletion(w io.Writer) error {
+	name := root.Name
+	script := zshCompletionTemplate
+	script = strings.ReplaceAll(script, "{{.Name}}", name)
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// GenFishCompletion writes a fish completion script for the root command
+// to w.
+func GenFishCompletion(w io.Writer) error {
+	name := root.Name
+	script := fishCompletionTemplate
+	script = strings.ReplaceAll(script, "{{.Name}}", name)
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+func sanitizeFuncName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+const bashCompletionTemplate = `# bash completion for {{.Name}}                          -*- shell-script -*-
+
+{{.FuncName}}() {
+	local cur prev words cword
+	COMPREPLY=()
+	cur="${COMP_WORDS[COMP_CWORD]}"
+
+	local out directive line
+	out=$({{.Name}} __complete "${COMP_WORDS[@]:1}" 2>/dev/null)
+	directive=$(echo "$out" | tail -n1 | tr -d ':')
+	candidates=$(echo "$out" | sed '$d')
+
+	COMPREPLY=($(compgen -W "${candidates}" -- "${cur}"))
+
+	if (( (directive & 1) != 0 )); then
+		# NoSpace
+		compopt -o nospace 2>/dev/null
+	fi
+	if (( (directive & 2) != 0 )); then
+		# NoFileComp
+		compopt +o default 2>/dev/null
+	fi
+	return 0
+}
+
+complete -F {{.FuncName}} {{.Name}}
+`
+
+const zshCompletionTemplate = `#compdef {{.Name}}
+
+_{{.Name}}() {
+	local -a candidates
+	local out directive
+	out=(${(f)"$({{.Name}} __complete "${words[2,-1]}" 2>/dev/null)"})
+	directive=${out[-1]#:}
+	candidates=(${out[1,-2]})
+	compadd -a candidates
+}
+
+compdef _{{.Name}} {{.Name}}
+`
+
+const fishCompletionTemplate = `# fish completion for {{.Name}}
+
+function __{{.Name}}_complete
+	set -l out ({{.Name}} __complete (commandline -opc) (commandline -ct) 2>/dev/null)
+	set -l directive $out[-1]
+	set -e out[-1]
+	for candidate in $out
+		echo $candidate
+	end
+end
+
+complete -c {{.Name}} -f -a '(__{{.Name}}_complete)'
+`
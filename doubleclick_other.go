@@ -0,0 +1,15 @@
+//go:build !windows
+
+package commands
+
+import "io"
+
+// isDoubleClickLaunch always reports false outside Windows; there's no
+// equivalent Explorer double-click footgun to guard against.
+func isDoubleClickLaunch() bool {
+	return false
+}
+
+// waitForKeypress is a no-op on platforms where isDoubleClickLaunch
+// never reports true.
+func waitForKeypress(_ io.Reader) {}
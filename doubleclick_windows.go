@@ -0,0 +1,41 @@
+//go:build windows
+
+package commands
+
+import (
+	"bufio"
+	"io"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                  = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleProcessList = kernel32.NewProc("GetConsoleProcessList")
+)
+
+// isDoubleClickLaunch reports whether this process appears to be the
+// only one attached to its console, which is the signature of a user
+// double-clicking the binary in Explorer rather than running it from an
+// existing cmd.exe/PowerShell session.
+//
+// GetConsoleProcessList isn't exposed by golang.org/x/sys/windows, so
+// it's called directly via a LazyDLL, the same pattern the standard
+// library itself uses for Win32 calls it doesn't wrap.
+func isDoubleClickLaunch() bool {
+	var procIDs [2]uint32
+	ret, _, _ := procGetConsoleProcessList.Call(
+		uintptr(unsafe.Pointer(&procIDs[0])),
+		uintptr(len(procIDs)),
+	)
+	if ret == 0 {
+		return false
+	}
+	return ret == 1
+}
+
+// waitForKeypress blocks until the user presses a key, so the console
+// window Explorer opened doesn't vanish before the message can be read.
+func waitForKeypress(r io.Reader) {
+	bufio.NewReader(r).ReadByte()
+}
@@ -24,6 +24,7 @@ func main() {
 
 var (
 	capitalize bool
+	verbose    bool
 )
 
 var printer = &commands.Command{
@@ -36,10 +37,10 @@ var printer = &commands.Command{
 		fs.BoolVar(&capitalize, "capitalize", false, "capitalize output")
 		return fs
 	}(),
-	Func: func(_ context.Context, stdout io.Writer, fs *flag.FlagSet, stdin io.Reader, stderr io.Writer) (int, error) {
-		// if verbose := ctx.Value(verboseKey); verbose != nil {
-		// 	fmt.Println("Executing print")
-		// }
+	Func: func(_ context.Context, stdout io.Writer, fs *flag.FlagSet, _ func(string) string, stdin io.Reader, stderr io.Writer) (int, error) {
+		if verbose {
+			fmt.Fprintln(stdout, "Executing print")
+		}
 		for _, arg := range fs.Args() {
 			if capitalize {
 				arg = strings.ToUpper(arg)
@@ -51,9 +52,23 @@ var printer = &commands.Command{
 	},
 }
 
+// foo declares --verbose as a persistent flag, so it and all of its
+// descendants (bah, print) accept it, and PersistentPreRun can act on it
+// before any descendant's own Func runs.
 var foo = &commands.Command{
 	Name:  "foo",
 	Short: "Nothing",
+	PersistentFlags: func() *flag.FlagSet {
+		fs := flag.NewFlagSet("foo", flag.ExitOnError)
+		fs.BoolVar(&verbose, "verbose", false, "enable verbose logging")
+		return fs
+	}(),
+	PersistentPreRun: func(_ context.Context, stdout io.Writer, _ *flag.FlagSet, _ func(string) string, _ io.Reader, _ io.Writer) (int, error) {
+		if verbose {
+			fmt.Fprintln(stdout, "[verbose] dispatching command")
+		}
+		return 0, nil
+	},
 }
 var bah = &commands.Command{
 	Name:  "bah",
@@ -24,7 +24,12 @@ func showHelp(cmd *Command) {
 	}
 
 	keys := make([]string, 0, len(cmd.children))
-	for k := range cmd.children {
+	for k, child := range cmd.children {
+		if strings.HasPrefix(k, "__") || k != child.Name {
+			// Skip hidden commands and alias entries; each child is
+			// listed once under its primary name.
+			continue
+		}
 		keys = append(keys, k)
 	}
 	if len(keys) > 0 {
@@ -33,18 +38,31 @@ func showHelp(cmd *Command) {
 	result += "\n"
 	//result += " [<args...>]\n"
 
+	if len(cmd.Aliases) > 0 {
+		result += fmt.Sprintf("\nAliases:\n  %s\n", strings.Join(cmd.Aliases, ", "))
+	}
+
 	if help := flagHelp(cmd.Flags); help != "" {
 		result += fmt.Sprintf("\nFlags:\n%s", help)
 	}
 
+	if global := globalFlagSet(cmd); global != nil {
+		if help := flagHelp(global); help != "" {
+			result += fmt.Sprintf("\nGlobal Flags:\n%s", help)
+		}
+	}
+
 	if len(keys) > 0 {
 		result += "\nCommands:\n"
-		format := fmt.Sprintf("  %%-%ds %%s\n", cmd.longestName)
 		sort.Strings(keys)
 		for _, k := range keys {
 			child := cmd.children[k]
 			short := strings.TrimSuffix(child.Short, ".")
-			result += fmt.Sprintf(format, child.Name, short)
+			pad := cmd.longestName - displayWidth(child.Name)
+			if pad < 0 {
+				pad = 0
+			}
+			result += fmt.Sprintf("  %s%s %s\n", child.Name, strings.Repeat(" ", pad), short)
 		}
 		result += fmt.Sprintf("\nUse '%s help <command>' for more information about a command.\n", names)
 	}
@@ -86,13 +104,52 @@ func helpForCommand(cmd *Command, fl *flag.FlagSet) (int, error) {
 	return ExitCodeSuccess, nil
 }
 
-func flagHelp(f *flag.FlagSet) string {
-	if f == nil {
+// globalFlagSet collects cmd's own PersistentFlags together with every
+// ancestor's PersistentFlags, nearest first, into a single *flag.FlagSet
+// for display under a command's "Global Flags" help section. Returns nil
+// if cmd has no persistent flags of its own or inherited from an
+// ancestor.
+func globalFlagSet(cmd *Command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	copyPersistentFlags(fs, cmd)
+	empty := true
+	fs.VisitAll(func(*flag.Flag) { empty = false })
+	if empty {
+		return nil
+	}
+	return fs
+}
+
+func flagHelp(p Parser) string {
+	if p == nil {
 		return ""
 	}
+
+	// A *FlagSet's shorthand registers each short flag as its own
+	// *flag.Flag entry (so it also works via the embedded FlagSet), so
+	// it must be skipped here and rendered once, alongside its long
+	// form.
+	var shorthand map[string]string
+	skip := map[string]bool{}
+	if posix, ok := p.(*FlagSet); ok {
+		shorthand = posix.shorthand
+		for _, short := range shorthand {
+			skip[short] = true
+		}
+	}
+
 	var b strings.Builder
-	f.VisitAll(func(fl *flag.Flag) {
-		fmt.Fprintf(&b, "  -%s", fl.Name) // Two spaces before -; see next two comments.
+	p.VisitAll(func(fl *flag.Flag) {
+		if skip[fl.Name] {
+			return
+		}
+		if short, ok := shorthand[fl.Name]; ok {
+			fmt.Fprintf(&b, "  -%s, --%s", short, fl.Name) // Two spaces before -; see next two comments.
+		} else if shorthand != nil {
+			fmt.Fprintf(&b, "  --%s", fl.Name)
+		} else {
+			fmt.Fprintf(&b, "  -%s", fl.Name) // Two spaces before -; see next two comments.
+		}
 		name, usage := flag.UnquoteUsage(fl)
 		if len(name) > 0 {
 			b.WriteString(" ")
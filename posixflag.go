@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NormalizeFunc rewrites a flag name before it is registered or looked
+// up, e.g. to treat "--some_flag" and "--some-flag" as the same flag.
+type NormalizeFunc func(fs *FlagSet, name string) string
+
+// FlagSet is a POSIX/GNU-style alternative to flag.FlagSet. It accepts
+// "--long", "--long=value", "--long value", short flags "-x", bundled
+// booleans "-xvf", attached short values "-fvalue", and a terminating
+// "--". Use NewFlagSet to construct one and the *VarP methods to
+// register flags with an optional single-character shorthand.
+type FlagSet struct {
+	// FlagSet holds every registered flag, long and short alike, so
+	// that Lookup, VisitAll, and Var behave exactly like the standard
+	// library for callers that don't care about POSIX parsing.
+	*flag.FlagSet
+
+	// NormalizeFunc, if set, rewrites flag names on registration and
+	// lookup.
+	NormalizeFunc NormalizeFunc
+
+	shorthand map[string]string // long name -> shorthand
+	args      []string
+}
+
+// NewFlagSet returns an empty *FlagSet for the named command.
+func NewFlagSet(name string) *FlagSet {
+	return &FlagSet{
+		FlagSet:   flag.NewFlagSet(name, flag.ContinueOnError),
+		shorthand: make(map[string]string),
+	}
+}
+
+func (fs *FlagSet) normalize(name string) string {
+	if fs.NormalizeFunc != nil {
+		return fs.NormalizeFunc(fs, name)
+	}
+	return name
+}
+
+func (fs *FlagSet) register(value flag.Value, name, shorthand, usage string) {
+	name = fs.normalize(name)
+	fs.FlagSet.Var(value, name, usage)
+	if shorthand != "" {
+		fs.FlagSet.Var(value, shorthand, usage)
+		fs.shorthand[name] = shorthand
+	}
+}
+
+// BoolVarP registers a boolean flag with an optional shorthand.
+func (fs *FlagSet) BoolVarP(p *bool, name, shorthand string, value bool, usage string) {
+	*p = value
+	fs.register(newBoolValue(p), name, shorthand, usage)
+}
+
+// StringVarP registers a string flag with an optional shorthand.
+func (fs *FlagSet) StringVarP(p *string, name, shorthand string, value string, usage string) {
+	*p = value
+	fs.register(newStringValue(p), name, shorthand, usage)
+}
+
+// IntVarP registers an int flag with an optional shorthand.
+func (fs *FlagSet) IntVarP(p *int, name, shorthand string, value int, usage string) {
+	*p = value
+	fs.register(newIntValue(p), name, shorthand, usage)
+}
+
+// Float64VarP registers a float64 flag with an optional shorthand.
+func (fs *FlagSet) Float64VarP(p *float64, name, shorthand string, value float64, usage string) {
+	*p = value
+	fs.register(newFloat64Value(p), name, shorthand, usage)
+}
+
+// DurationVarP registers a time.Duration flag with an optional
+// shorthand.
+func (fs *FlagSet) DurationVarP(p *time.Duration, name, shorthand string, value time.Duration, usage string) {
+	*p = value
+	fs.register(newDurationValue(p), name, shorthand, usage)
+}
+
+// StringSliceVarP registers a flag that may be repeated, or given a
+// comma-separated value, to build up a []string.
+func (fs *FlagSet) StringSliceVarP(p *[]string, name, shorthand string, value []string, usage string) {
+	*p = value
+	fs.register(newStringSliceValue(p), name, shorthand, usage)
+}
+
+// Args returns the positional arguments left over after Parse.
+func (fs *FlagSet) Args() []string {
+	return fs.args
+}
+
+// Parse parses args using GNU/POSIX conventions: "--long", "--long=value",
+// "--long value", short flags "-x", bundled booleans "-xvf", attached
+// short values "-fvalue" and "-f value", and a terminating "--". Unlike
+// the standard library, flags may appear anywhere among the positional
+// arguments, not just at the front.
+func (fs *FlagSet) Parse(args []string) error {
+	var positional []string
+
+	i := 0
+	for i < len(args) {
+		a := args[i]
+
+		if a == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(a, "--"):
+			name := a[2:]
+			val, hasVal := "", false
+			if eq := strings.IndexByte(name, '='); eq >= 0 {
+				val, hasVal = name[eq+1:], true
+				name = name[:eq]
+			}
+			name = fs.normalize(name)
+			fl := fs.FlagSet.Lookup(name)
+			if fl == nil {
+				return fmt.Errorf("unknown flag: --%s", name)
+			}
+			if !hasVal && !isBoolFlag(fl) {
+				i++
+				if i >= len(args) {
+					return fmt.Errorf("flag needs an argument: --%s", name)
+				}
+				val = args[i]
+			} else if !hasVal {
+				val = "true"
+			}
+			if err := fl.Value.Set(val); err != nil {
+				return fmt.Errorf("invalid value %q for flag --%s: %w", val, name, err)
+			}
+			i++
+
+		case len(a) > 1 && a[0] == '-':
+			consumed, err := fs.parseShortCluster(a[1:], args[i+1:])
+			if err != nil {
+				return err
+			}
+			i += 1 + consumed
+
+		default:
+			positional = append(positional, a)
+			i++
+		}
+	}
+
+	fs.args = positional
+
+	// Run the underlying flag.FlagSet's own Parse with a "--" guard so
+	// that callers using the embedded *flag.FlagSet directly (e.g. a
+	// CommandFunc) see the same positional args via Args()/NArg()/Arg().
+	return fs.FlagSet.Parse(append([]string{"--"}, positional...))
+}
+
+// parseShortCluster parses a run of short flags following a single '-',
+// e.g. "xvf" in "-xvf" or "fvalue" in "-fvalue". It returns how many of
+// the following args it consumed for a flag's value.
+func (fs *FlagSet) parseShortCluster(cluster string, rest []string) (int, error) {
+	for idx, r := range cluster {
+		name := string(r)
+		fl := fs.FlagSet.Lookup(name)
+		if fl == nil {
+			return 0, fmt.Errorf("unknown shorthand flag: -%s", name)
+		}
+		if isBoolFlag(fl) {
+			if err := fl.Value.Set("true"); err != nil {
+				return 0, fmt.Errorf("invalid value for flag -%s: %w", name, err)
+			}
+			continue
+		}
+		// Non-boolean: whatever remains of the cluster is its value
+		// ("-fvalue"); otherwise consume the next arg ("-f value").
+		if remainder := cluster[idx+len(name):]; remainder != "" {
+			return 0, fl.Value.Set(remainder)
+		}
+		if len(rest) == 0 {
+			return 0, fmt.Errorf("flag needs an argument: -%s", name)
+		}
+		if err := fl.Value.Set(rest[0]); err != nil {
+			return 0, fmt.Errorf("invalid value %q for flag -%s: %w", rest[0], name, err)
+		}
+		return 1, nil
+	}
+	return 0, nil
+}
+
+type boolFlagValue interface {
+	IsBoolFlag() bool
+}
+
+func isBoolFlag(fl *flag.Flag) bool {
+	bf, ok := fl.Value.(boolFlagValue)
+	return ok && bf.IsBoolFlag()
+}
+
+type boolValue bool
+
+func newBoolValue(p *bool) *boolValue { return (*boolValue)(p) }
+func (b *boolValue) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	*b = boolValue(v)
+	return err
+}
+func (b *boolValue) String() string   { return strconv.FormatBool(bool(*b)) }
+func (b *boolValue) IsBoolFlag() bool { return true }
+
+type stringValueP string
+
+func newStringValue(p *string) *stringValueP { return (*stringValueP)(p) }
+func (s *stringValueP) Set(v string) error   { *s = stringValueP(v); return nil }
+func (s *stringValueP) String() string       { return string(*s) }
+
+type intValue int
+
+func newIntValue(p *int) *intValue { return (*intValue)(p) }
+func (i *intValue) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	*i = intValue(v)
+	return err
+}
+func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
+
+type float64Value float64
+
+func newFloat64Value(p *float64) *float64Value { return (*float64Value)(p) }
+func (f *float64Value) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	*f = float64Value(v)
+	return err
+}
+func (f *float64Value) String() string { return strconv.FormatFloat(float64(*f), 'g', -1, 64) }
+
+type durationValue time.Duration
+
+func newDurationValue(p *time.Duration) *durationValue { return (*durationValue)(p) }
+func (d *durationValue) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	*d = durationValue(v)
+	return err
+}
+func (d *durationValue) String() string { return time.Duration(*d).String() }
+
+type stringSliceValue struct {
+	value *[]string
+}
+
+func newStringSliceValue(p *[]string) *stringSliceValue { return &stringSliceValue{value: p} }
+func (s *stringSliceValue) Set(v string) error {
+	*s.value = append(*s.value, strings.Split(v, ",")...)
+	return nil
+}
+func (s *stringSliceValue) String() string {
+	if s.value == nil {
+		return ""
+	}
+	return strings.Join(*s.value, ",")
+}
@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlagSetParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantArgs []string
+		check    func(t *testing.T, verbose, quiet *bool, out *string)
+	}{
+		{
+			name:     "long flag with equals",
+			args:     []string{"--out=file.txt"},
+			wantArgs: nil,
+			check: func(t *testing.T, verbose, quiet *bool, out *string) {
+				if *out != "file.txt" {
+					t.Errorf("out = %q, want %q", *out, "file.txt")
+				}
+			},
+		},
+		{
+			name:     "long flag with separate value",
+			args:     []string{"--out", "file.txt"},
+			wantArgs: nil,
+			check: func(t *testing.T, verbose, quiet *bool, out *string) {
+				if *out != "file.txt" {
+					t.Errorf("out = %q, want %q", *out, "file.txt")
+				}
+			},
+		},
+		{
+			name:     "long boolean flag needs no value",
+			args:     []string{"--verbose", "positional"},
+			wantArgs: []string{"positional"},
+			check: func(t *testing.T, verbose, quiet *bool, out *string) {
+				if !*verbose {
+					t.Errorf("verbose = false, want true")
+				}
+			},
+		},
+		{
+			name:     "bundled short booleans",
+			args:     []string{"-vq"},
+			wantArgs: nil,
+			check: func(t *testing.T, verbose, quiet *bool, out *string) {
+				if !*verbose || !*quiet {
+					t.Errorf("verbose = %v, quiet = %v, want both true", *verbose, *quiet)
+				}
+			},
+		},
+		{
+			name:     "attached short value",
+			args:     []string{"-ofile.txt"},
+			wantArgs: nil,
+			check: func(t *testing.T, verbose, quiet *bool, out *string) {
+				if *out != "file.txt" {
+					t.Errorf("out = %q, want %q", *out, "file.txt")
+				}
+			},
+		},
+		{
+			name:     "short value as next arg",
+			args:     []string{"-o", "file.txt"},
+			wantArgs: nil,
+			check: func(t *testing.T, verbose, quiet *bool, out *string) {
+				if *out != "file.txt" {
+					t.Errorf("out = %q, want %q", *out, "file.txt")
+				}
+			},
+		},
+		{
+			name:     "flags may follow positional args",
+			args:     []string{"first", "--verbose", "second"},
+			wantArgs: []string{"first", "second"},
+			check: func(t *testing.T, verbose, quiet *bool, out *string) {
+				if !*verbose {
+					t.Errorf("verbose = false, want true")
+				}
+			},
+		},
+		{
+			name:     "terminator stops flag parsing",
+			args:     []string{"--verbose", "--", "-ofile.txt"},
+			wantArgs: []string{"-ofile.txt"},
+			check: func(t *testing.T, verbose, quiet *bool, out *string) {
+				if !*verbose {
+					t.Errorf("verbose = false, want true")
+				}
+				if *out != "" {
+					t.Errorf("out = %q, want empty (value after -- is positional)", *out)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("test")
+			var verbose, quiet bool
+			var out string
+			fs.BoolVarP(&verbose, "verbose", "v", false, "verbose")
+			fs.BoolVarP(&quiet, "quiet", "q", false, "quiet")
+			fs.StringVarP(&out, "out", "o", "", "output path")
+
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse(%v) error: %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(fs.Args(), tt.wantArgs) {
+				t.Errorf("Args() = %v, want %v", fs.Args(), tt.wantArgs)
+			}
+			tt.check(t, &verbose, &quiet, &out)
+		})
+	}
+}
+
+func TestFlagSetParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown long flag", []string{"--nope"}},
+		{"unknown short flag", []string{"-z"}},
+		{"long flag missing value", []string{"--out"}},
+		{"short flag missing value", []string{"-o"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFlagSet("test")
+			var out string
+			fs.StringVarP(&out, "out", "o", "", "output path")
+
+			if err := fs.Parse(tt.args); err == nil {
+				t.Errorf("Parse(%v) error = nil, want error", tt.args)
+			}
+		})
+	}
+}
+
+func TestFlagSetNormalizeFunc(t *testing.T) {
+	fs := NewFlagSet("test")
+	fs.NormalizeFunc = func(_ *FlagSet, name string) string {
+		if name == "some_flag" {
+			return "some-flag"
+		}
+		return name
+	}
+
+	var value string
+	fs.StringVarP(&value, "some-flag", "", "", "usage")
+
+	if err := fs.Parse([]string{"--some_flag", "hi"}); err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if value != "hi" {
+		t.Errorf("value = %q, want %q", value, "hi")
+	}
+}
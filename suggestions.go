@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+)
+
+// suggest returns the registered child name or alias closest to token,
+// provided it falls within the allowed edit distance, for use in "did
+// you mean" messages.
+func (cmd *Command) suggest(token string) (string, bool) {
+	threshold := cmd.SuggestionsMinimumDistance
+	if threshold <= 0 {
+		threshold = 2
+	}
+	if min := len(token) / 3; min > threshold {
+		threshold = min
+	}
+
+	names := make([]string, 0, len(cmd.children))
+	for name := range cmd.children {
+		if strings.HasPrefix(name, "__") {
+			// Internal commands like __complete aren't meant to be
+			// typed directly; don't suggest them.
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best := ""
+	bestDist := -1
+	for _, name := range names {
+		d := levenshtein(token, name)
+		if bestDist == -1 || d < bestDist {
+			bestDist = d
+			best = name
+		}
+	}
+
+	if bestDist == -1 || bestDist > threshold {
+		return "", false
+	}
+	return best, true
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			del := prev[j] + 1
+			ins := curr[j-1] + 1
+			sub := prev[j-1] + cost
+			curr[j] = min3(del, ins, sub)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
@@ -0,0 +1,72 @@
+package commands
+
+// displayWidth returns the number of terminal cells s occupies, treating
+// combining marks as zero width and East Asian Wide/Fullwidth runes as
+// two cells wide. It is used instead of len() (a byte count) to keep
+// help tables aligned when names contain multi-byte runes.
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case isCombining(r):
+			// zero width
+		case isWide(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+func isCombining(r rune) bool {
+	switch {
+	case r >= 0x0300 && r <= 0x036F: // Combining Diacritical Marks
+		return true
+	case r >= 0x0483 && r <= 0x0489: // Cyrillic combining marks
+		return true
+	case r >= 0x0591 && r <= 0x05BD: // Hebrew points
+		return true
+	case r >= 0x200B && r <= 0x200F: // zero width space/joiners, Cf
+		return true
+	case r >= 0x202A && r <= 0x202E: // directional formatting, Cf
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F: // variation selectors
+		return true
+	case r >= 0xFE20 && r <= 0xFE2F: // Combining Half Marks
+		return true
+	}
+	return false
+}
+
+func isWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F: // Hangul Jamo
+		return true
+	case r >= 0x2E80 && r <= 0x303E: // CJK Radicals, Kangxi, punctuation
+		return true
+	case r >= 0x3041 && r <= 0x33FF: // Hiragana..CJK Compat
+		return true
+	case r >= 0x3400 && r <= 0x4DBF: // CJK Extension A
+		return true
+	case r >= 0x4E00 && r <= 0x9FFF: // CJK Unified Ideographs
+		return true
+	case r >= 0xA000 && r <= 0xA4CF: // Yi
+		return true
+	case r >= 0xAC00 && r <= 0xD7A3: // Hangul Syllables
+		return true
+	case r >= 0xF900 && r <= 0xFAFF: // CJK Compatibility Ideographs
+		return true
+	case r >= 0xFE30 && r <= 0xFE4F: // CJK Compatibility Forms
+		return true
+	case r >= 0xFF00 && r <= 0xFF60: // Fullwidth Forms
+		return true
+	case r >= 0xFFE0 && r <= 0xFFE6: // Fullwidth Signs
+		return true
+	case r >= 0x20000 && r <= 0x2FFFD: // CJK Extension B..
+		return true
+	case r >= 0x30000 && r <= 0x3FFFD: // CJK Extension G..
+		return true
+	}
+	return false
+}